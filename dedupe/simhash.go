@@ -0,0 +1,68 @@
+// Package dedupe detects near-duplicate articles and canonicalizes URLs
+// so the same story isn't stored (or crawled) twice. Eenadu republishes
+// the same story across multiple sections under different URLs, so both
+// halves matter.
+package dedupe
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+)
+
+// NumBands and BandBits describe how a 64-bit SimHash is split for
+// banded near-duplicate lookups: two hashes within the similarity
+// threshold are guaranteed to share at least one band exactly.
+const (
+	NumBands = 4
+	BandBits = 16
+)
+
+// SimHash computes a 64-bit SimHash fingerprint over the tokenized text.
+// Near-duplicate texts produce hashes a small Hamming distance apart.
+func SimHash(text string) uint64 {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	for _, tok := range tokens {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(tok))
+		hash := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if hash&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var result uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			result |= 1 << uint(bit)
+		}
+	}
+	return result
+}
+
+func tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+// HammingDistance returns the number of differing bits between a and b.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Bands splits hash into NumBands BandBits-wide bands.
+func Bands(hash uint64) [NumBands]uint16 {
+	var bands [NumBands]uint16
+	for i := 0; i < NumBands; i++ {
+		bands[i] = uint16(hash >> uint(i*BandBits))
+	}
+	return bands
+}
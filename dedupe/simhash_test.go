@@ -0,0 +1,53 @@
+package dedupe
+
+import "testing"
+
+func TestSimHashIdenticalText(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog"
+	if SimHash(text) != SimHash(text) {
+		t.Fatal("SimHash of the same text should be identical")
+	}
+}
+
+func TestSimHashEmpty(t *testing.T) {
+	if got := SimHash(""); got != 0 {
+		t.Errorf("SimHash(\"\") = %d, want 0", got)
+	}
+}
+
+func TestSimHashNearDuplicateIsClose(t *testing.T) {
+	base := "breaking news from hyderabad today the chief minister announced a new irrigation project for the farmers in telangana state and promised additional funding next year"
+	near := "breaking news from hyderabad today the chief minister announced a new irrigation project for the farmers in telangana state and promised additional funding next month"
+
+	if dist := HammingDistance(SimHash(base), SimHash(near)); dist > 3 {
+		t.Errorf("HammingDistance(near-duplicates) = %d, want <= 3", dist)
+	}
+}
+
+func TestSimHashUnrelatedTextIsFar(t *testing.T) {
+	a := SimHash("the quick brown fox jumps over the lazy dog")
+	b := SimHash("stock markets rallied today after the central bank announcement")
+
+	if dist := HammingDistance(a, b); dist <= 3 {
+		t.Errorf("HammingDistance(unrelated texts) = %d, want > 3", dist)
+	}
+}
+
+func TestHammingDistanceSelf(t *testing.T) {
+	h := SimHash("some article content")
+	if dist := HammingDistance(h, h); dist != 0 {
+		t.Errorf("HammingDistance(h, h) = %d, want 0", dist)
+	}
+}
+
+func TestBandsSplitHash(t *testing.T) {
+	var hash uint64 = 0x1234_5678_9abc_def0
+	bands := Bands(hash)
+
+	for i := 0; i < NumBands; i++ {
+		want := uint16(hash >> uint(i*BandBits))
+		if bands[i] != want {
+			t.Errorf("Bands(%#x)[%d] = %#x, want %#x", hash, i, bands[i], want)
+		}
+	}
+}
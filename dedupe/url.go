@@ -0,0 +1,37 @@
+package dedupe
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// NormalizeURL canonicalizes rawURL so the same page isn't crawled
+// twice under superficially different URLs: it lowercases the host,
+// strips utm_* tracking query parameters, resolves "." and ".."
+// segments in the path, and drops the fragment.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+
+	if u.Path != "" {
+		u.Path = path.Clean(u.Path)
+	}
+
+	if u.RawQuery != "" {
+		q := u.Query()
+		for key := range q {
+			if strings.HasPrefix(strings.ToLower(key), "utm_") {
+				q.Del(key)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,44 @@
+package dedupe
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "lowercases host",
+			in:   "https://WWW.Eenadu.NET/path",
+			want: "https://www.eenadu.net/path",
+		},
+		{
+			name: "strips utm params but keeps others",
+			in:   "https://www.eenadu.net/path?id=42&utm_source=twitter&utm_medium=social",
+			want: "https://www.eenadu.net/path?id=42",
+		},
+		{
+			name: "resolves dot segments",
+			in:   "https://www.eenadu.net/a/b/../c",
+			want: "https://www.eenadu.net/a/c",
+		},
+		{
+			name: "drops fragment",
+			in:   "https://www.eenadu.net/path#section",
+			want: "https://www.eenadu.net/path",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeURL(tc.in)
+			if err != nil {
+				t.Fatalf("NormalizeURL(%q) returned error: %v", tc.in, err)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,316 +1,296 @@
 package main
 
 import (
-	"database/sql"
+	"bytes"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
+	"regexp"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/ramuthumu/eenadu-go-scraper/dedupe"
+	"github.com/ramuthumu/eenadu-go-scraper/extractors"
+	"github.com/ramuthumu/eenadu-go-scraper/fetcher"
+	"github.com/ramuthumu/eenadu-go-scraper/store"
 )
 
 const (
-	baseURL       = "https://www.eenadu.net"
-	urlsDBName    = "urls.db"
-	articleDBName = "articles.db"
-	batchSize     = 100
+	baseURL      = "https://www.eenadu.net"
+	batchSize    = 100
+	httpCacheDir = "httpcache"
 )
 
 var (
-	once      sync.Once
-	urlsDB    *sql.DB
-	articleDB *sql.DB
-)
-
-type Article struct {
-	URL           string
-	Title         string
-	DatePublished string
-	Content       string
-}
+	// httpFetcher is built in applyCrawlFlags once the -user-agent/-per-host-limit/
+	// -rate/-max-retries/-request-timeout flags have been parsed.
+	httpFetcher *fetcher.Fetcher
 
-func initDB() error {
-	var err error
-	urlsDB, err = sql.Open("sqlite3", urlsDBName)
-	if err != nil {
-		return err
-	}
+	// extractorRegistry is populated in applyCrawlFlags once flags (including any
+	// extractors config file) have been parsed.
+	extractorRegistry *extractors.Registry
 
-	articleDB, err = sql.Open("sqlite3", articleDBName)
-	if err != nil {
-		return err
-	}
+	// linkFilter is applied to discovered links before they're queued, so
+	// the crawler can be pointed at a subset of a site via -allow/-deny.
+	linkFilter urlFilter
+)
 
-	return initializeTables()
+// urlFilter restricts which discovered links the crawler will queue: a
+// URL is rejected if it matches any deny pattern, and otherwise accepted
+// if there are no allow patterns or it matches at least one.
+type urlFilter struct {
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
 }
 
-func initializeTables() error {
-	createURLsTable := `
-	CREATE TABLE IF NOT EXISTS urls (
-		url TEXT PRIMARY KEY,
-		visited BOOLEAN,
-		scraped BOOLEAN
-	);`
-	_, err := urlsDB.Exec(createURLsTable)
-	if err != nil {
-		return err
-	}
-
-	createArticlesTable := `
-	CREATE TABLE IF NOT EXISTS articles (
-		url TEXT PRIMARY KEY,
-		title TEXT,
-		date_published TEXT,
-		content TEXT
-	);`
-	_, err = articleDB.Exec(createArticlesTable)
-	if err != nil {
-		return err
+func (f urlFilter) allowed(u string) bool {
+	for _, re := range f.deny {
+		if re.MatchString(u) {
+			return false
+		}
 	}
-
-	return seedBaseURL()
-}
-
-func seedBaseURL() error {
-	row := urlsDB.QueryRow("SELECT COUNT(*) FROM urls")
-	var count int
-	err := row.Scan(&count)
-	if err != nil {
-		return err
+	if len(f.allow) == 0 {
+		return true
 	}
-
-	if count == 0 {
-		_, err := urlsDB.Exec("INSERT INTO urls (url, visited, scraped) VALUES (?, FALSE, FALSE)", baseURL)
-		if err != nil {
-			return err
+	for _, re := range f.allow {
+		if re.MatchString(u) {
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
-func getNextURLs(batchSize int) (urls []string, err error) {
-	once.Do(func() {
-		if err := initDB(); err != nil {
-			log.Fatalf("Failed to initialize databases: %v", err)
-		}
-	})
+// regexListFlag collects repeated -allow/-deny flag occurrences into a
+// list of compiled regexes.
+type regexListFlag []*regexp.Regexp
 
-	rows, err := urlsDB.Query("SELECT url FROM urls WHERE visited = FALSE LIMIT ?", batchSize)
-	if err != nil {
-		return nil, err
+func (r *regexListFlag) String() string {
+	if r == nil {
+		return ""
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var url string
-		if err := rows.Scan(&url); err != nil {
-			return nil, err
-		}
-		urls = append(urls, url)
+	patterns := make([]string, len(*r))
+	for i, re := range *r {
+		patterns[i] = re.String()
 	}
-
-	return urls, nil
+	return strings.Join(patterns, ",")
 }
 
-func markURLsAsVisited(urls []string) error {
-	once.Do(func() {
-		if err := initDB(); err != nil {
-			log.Fatalf("Failed to initialize databases: %v", err)
-		}
-	})
-
-	tx, err := urlsDB.Begin()
+func (r *regexListFlag) Set(value string) error {
+	re, err := regexp.Compile(value)
 	if err != nil {
 		return err
 	}
-
-	for _, url := range urls {
-		if _, err := tx.Exec("UPDATE urls SET visited = TRUE WHERE url = ?", url); err != nil {
-			return err
-		}
-	}
-
-	return tx.Commit()
+	*r = append(*r, re)
+	return nil
 }
 
-func insertNewURLs(urls []string) error {
-	once.Do(func() {
-		if err := initDB(); err != nil {
-			log.Fatalf("Failed to initialize databases: %v", err)
-		}
-	})
+// stringListFlag collects repeated -feed flag occurrences into a list
+// of feed URLs.
+type stringListFlag []string
 
-	tx, err := urlsDB.Begin()
-	if err != nil {
-		return err
-	}
-
-	stmt, err := tx.Prepare("INSERT OR IGNORE INTO urls (url, visited, scraped) VALUES (?, FALSE, FALSE)")
-	if err != nil {
-		return err
+func (s *stringListFlag) String() string {
+	if s == nil {
+		return ""
 	}
-
-	for _, u := range urls {
-		if _, err := stmt.Exec(u); err != nil {
-			return err
-		}
-	}
-
-	return tx.Commit()
+	return strings.Join(*s, ",")
 }
 
-func insertArticle(article Article) error {
-	once.Do(func() {
-		if err := initDB(); err != nil {
-			log.Fatalf("Failed to initialize databases: %v", err)
-		}
-	})
-
-	_, err := articleDB.Exec("INSERT OR IGNORE INTO articles (url, title, date_published, content) VALUES (?, ?, ?, ?)",
-		article.URL, article.Title, article.DatePublished, article.Content)
-	if err != nil {
-		return err
-	}
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
 	return nil
 }
 
+// Article and link are aliases onto the store package's types, so the
+// persistence layer and the crawl loop agree on a single definition.
+type Article = store.Article
+type link = store.Link
+
 func extractContent(u string) (Article, *goquery.Document, error) {
-	resp, err := http.Get(u)
+	body, _, err := httpFetcher.Get(u)
 	if err != nil {
 		return Article{}, nil, err
 	}
-	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return Article{}, nil, err
 	}
 
-	fullstorySelection := doc.Find("div.fullstory, section.fullstory")
-	title := fullstorySelection.Find("h1").Text()
-	content := fullstorySelection.Find("p").Text()
-	datePublished := fullstorySelection.Find("div.pub-t").Text()
+	extractor := extractorRegistry.For(u)
+	if extractor == nil {
+		return Article{}, nil, fmt.Errorf("no extractor matched %s", u)
+	}
+	extracted, err := extractor.Extract(u, doc)
+	if err != nil {
+		return Article{}, nil, err
+	}
 
 	return Article{
-		URL:           u,
-		Title:         title,
-		DatePublished: datePublished,
-		Content:       content,
+		URL:           extracted.URL,
+		Title:         extracted.Title,
+		DatePublished: extracted.DatePublished,
+		Content:       extracted.Content,
+		Author:        extracted.Author,
 	}, doc, nil
 }
 
+// extractURLs collects absolute http(s) links from doc, gated only by
+// linkFilter: unlike an early version of this crawler, links aren't
+// required to start with baseURL, so -allow/-deny can point the crawler
+// at other sites entirely rather than just eenadu.net.
 func extractURLs(doc *goquery.Document) []string {
 	var urls []string
 	doc.Find("a").Each(func(i int, s *goquery.Selection) {
 		href, exists := s.Attr("href")
-		if exists && strings.HasPrefix(href, baseURL) {
+		if !exists {
+			return
+		}
+		if !strings.HasPrefix(href, "http://") && !strings.HasPrefix(href, "https://") {
+			return
+		}
+		if linkFilter.allowed(href) {
 			urls = append(urls, href)
 		}
 	})
 	return urls
 }
 
-func markURLAsScraped(url string) error {
-	once.Do(func() {
-		if err := initDB(); err != nil {
-			log.Fatalf("Failed to initialize databases: %v", err)
-		}
-	})
-
-	_, err := urlsDB.Exec("UPDATE urls SET scraped = TRUE WHERE url = ?", url)
-	return err
-}
-
-func processURL(u string, wg *sync.WaitGroup) {
-	// Removed the defer wg.Done() from here
-	article, doc, err := extractContent(u)
+// processURL scrapes a single frontier entry against the given stores
+// and, unless it is already at maxDepth (0 means unbounded), queues the
+// links it discovers one depth level deeper.
+func processURL(urls store.URLStore, articles store.ArticleStore, l link, maxDepth int) {
+	article, doc, err := extractContent(l.URL)
 	if err != nil {
-		log.Printf("Error while requesting %s: %s\n", u, err)
-		wg.Done() // Call Done here
+		log.Printf("Error while requesting %s: %s\n", l.URL, err)
 		return
 	}
 
-	if err := insertArticle(article); err != nil {
+	if err := insertArticle(articles, article); err != nil {
 		log.Printf("Error inserting article: %v", err)
-		wg.Done() // Call Done here
 		return
 	}
 
-	if err := markURLAsScraped(u); err != nil {
+	if err := urls.MarkScraped(l.URL); err != nil {
 		log.Printf("Error marking URL as scraped: %v", err)
-		wg.Done() // Call Done here
+		return
+	}
+
+	if maxDepth > 0 && l.Depth >= maxDepth {
 		return
 	}
 
 	newURLs := extractURLs(doc)
-	if err := insertNewURLs(newURLs); err != nil {
+	newLinks := make([]link, len(newURLs))
+	for i, u := range newURLs {
+		newLinks[i] = link{URL: u, Depth: l.Depth + 1}
+	}
+	if err := urls.InsertNew(newLinks); err != nil {
 		log.Printf("Error inserting new URLs: %v", err)
-		wg.Done() // Call Done here
-		return
 	}
-	wg.Done() // Call Done here
 }
 
-func processBaseURL() {
+func processBaseURL(urls store.URLStore, articles store.ArticleStore) {
 	article, doc, err := extractContent(baseURL)
 	if err != nil {
 		log.Printf("Error while requesting %s: %s\n", baseURL, err)
 		return
 	}
 
-	if err := insertArticle(article); err != nil {
+	if err := insertArticle(articles, article); err != nil {
 		log.Printf("Error inserting article: %v", err)
 		return
 	}
 
 	newURLs := extractURLs(doc)
-	if err := insertNewURLs(newURLs); err != nil {
+	newLinks := make([]link, len(newURLs))
+	for i, u := range newURLs {
+		newLinks[i] = link{URL: u, Depth: 1}
+	}
+	if err := urls.InsertNew(newLinks); err != nil {
 		log.Printf("Error inserting new URLs: %v", err)
-		return
 	}
 }
 
-func main() {
-	if err := initDB(); err != nil {
-		log.Fatalf("Failed to initialize databases: %v", err)
-		return
+// insertArticle stores article unless its content is a near-duplicate
+// (SimHash Hamming distance <=3) of an article already on file, in
+// which case it is skipped.
+func insertArticle(articles store.ArticleStore, article Article) error {
+	article.SimHash = dedupe.SimHash(article.Content)
+
+	dupURL, err := articles.Insert(article)
+	if err != nil {
+		return err
+	}
+	if dupURL != "" {
+		log.Printf("Skipping %s: near-duplicate of %s", article.URL, dupURL)
 	}
-	defer urlsDB.Close()
-	defer articleDB.Close()
+	return nil
+}
 
-	processBaseURL()
+// crawlFlags holds the flags shared by the default crawl invocation and
+// the "server" subcommand.
+type crawlFlags struct {
+	workers          *int
+	maxDepth         *int
+	maxPages         *int
+	extractorsConfig *string
+	allow            regexListFlag
+	deny             regexListFlag
+	feedURLs         stringListFlag
+	feedInterval     *time.Duration
+	storeKind        *string
+	storeDSN         *string
+	userAgent        *string
+	perHostLimit     *int
+	ratePerSecond    *float64
+	maxRetries       *int
+	requestTimeout   *time.Duration
+}
 
-	var wg sync.WaitGroup // Initialize the WaitGroup here
+func registerCrawlFlags(fs *flag.FlagSet) *crawlFlags {
+	cf := &crawlFlags{
+		workers:          fs.Int("workers", 16, "number of concurrent worker goroutines"),
+		maxDepth:         fs.Int("max-depth", 0, "maximum link depth to crawl from baseURL (0 = unbounded)"),
+		maxPages:         fs.Int("max-pages", 0, "maximum number of pages to scrape (0 = unbounded)"),
+		extractorsConfig: fs.String("extractors-config", "", "YAML/JSON file mapping URL patterns to CSS selectors for additional sites"),
+		storeKind:        fs.String("store", "sqlite", "persistence backend: sqlite, postgres, or jsonl"),
+		storeDSN:         fs.String("dsn", "", "backend-specific connection string (see store.New)"),
+		userAgent:        fs.String("user-agent", "", "User-Agent header to send (empty uses the fetcher's default)"),
+		perHostLimit:     fs.Int("per-host-limit", 0, "max concurrent in-flight requests per host (0 uses the fetcher's default)"),
+		ratePerSecond:    fs.Float64("rate", 0, "token bucket refill rate per host, in requests/sec (0 uses the fetcher's default)"),
+		maxRetries:       fs.Int("max-retries", 0, "retries on 5xx/429 before giving up (0 uses the fetcher's default)"),
+		requestTimeout:   fs.Duration("request-timeout", 0, "per-request timeout (0 means no timeout)"),
+	}
+	fs.Var(&cf.allow, "allow", "regex a discovered URL must match to be crawled (repeatable)")
+	fs.Var(&cf.deny, "deny", "regex that excludes a discovered URL from being crawled (repeatable)")
+	fs.Var(&cf.feedURLs, "feed", "RSS/Atom feed URL to poll for newly-published article links (repeatable)")
+	cf.feedInterval = fs.Duration("feed-interval", 5*time.Minute, "how often to poll configured feeds")
+	return cf
+}
 
-	for {
-		currentURLs, err := getNextURLs(batchSize)
-		if err != nil {
-			log.Printf("Error getting next URLs: %v", err)
-			continue
-		}
+func applyCrawlFlags(cf *crawlFlags) {
+	linkFilter = urlFilter{allow: cf.allow, deny: cf.deny}
 
-		if len(currentURLs) == 0 {
-			fmt.Println("No more URLs to process. Exiting.")
-			break
-		}
+	httpFetcher = fetcher.New(fetcher.Config{
+		UserAgent:      *cf.userAgent,
+		PerHostLimit:   *cf.perHostLimit,
+		RatePerSecond:  *cf.ratePerSecond,
+		MaxRetries:     *cf.maxRetries,
+		CacheDir:       httpCacheDir,
+		RequestTimeout: *cf.requestTimeout,
+	})
 
-		if err := markURLsAsVisited(currentURLs); err != nil {
-			log.Printf("Error marking URLs as visited: %v", err)
-			continue
+	registry := extractors.NewRegistry(extractors.EenaduExtractor{})
+	if *cf.extractorsConfig != "" {
+		configured, err := extractors.LoadConfig(*cf.extractorsConfig)
+		if err != nil {
+			log.Fatalf("Failed to load extractors config: %v", err)
 		}
-
-		for _, u := range currentURLs {
-			wg.Add(1)
-			go func(url string) {
-				processURL(url, &wg) // Just call the function here
-			}(u)
+		for _, e := range configured {
+			registry.Add(e)
 		}
-		wg.Wait()
-
-		time.Sleep(1 * time.Second)
 	}
+	registry.Add(extractors.GenericExtractor{})
+	extractorRegistry = registry
 }
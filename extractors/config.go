@@ -0,0 +1,79 @@
+package extractors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteConfig maps a URL pattern to the CSS selectors needed to pull an
+// article out of pages matching it, so new sites can be added without
+// writing Go code.
+type SiteConfig struct {
+	Pattern         string `yaml:"pattern" json:"pattern"`
+	TitleSelector   string `yaml:"title_selector" json:"title_selector"`
+	DateSelector    string `yaml:"date_selector" json:"date_selector"`
+	ContentSelector string `yaml:"content_selector" json:"content_selector"`
+	AuthorSelector  string `yaml:"author_selector" json:"author_selector"`
+}
+
+// FileConfig is the top-level shape of an extractors config file.
+type FileConfig struct {
+	Sites []SiteConfig `yaml:"sites" json:"sites"`
+}
+
+// configuredExtractor is a SiteExtractor entirely driven by CSS
+// selectors loaded from a config file.
+type configuredExtractor struct {
+	pattern *regexp.Regexp
+	cfg     SiteConfig
+}
+
+func (c *configuredExtractor) Match(u string) bool {
+	return c.pattern.MatchString(u)
+}
+
+func (c *configuredExtractor) Extract(u string, doc *goquery.Document) (Article, error) {
+	return Article{
+		URL:           u,
+		Title:         doc.Find(c.cfg.TitleSelector).Text(),
+		DatePublished: doc.Find(c.cfg.DateSelector).Text(),
+		Content:       doc.Find(c.cfg.ContentSelector).Text(),
+		Author:        doc.Find(c.cfg.AuthorSelector).Text(),
+	}, nil
+}
+
+// LoadConfig reads a YAML or JSON file of site selector configs
+// (format chosen by the ".json" extension, YAML otherwise) and returns
+// one SiteExtractor per entry, in file order.
+func LoadConfig(path string) ([]SiteExtractor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("extractors: reading %s: %w", path, err)
+	}
+
+	var cfg FileConfig
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extractors: parsing %s: %w", path, err)
+	}
+
+	loaded := make([]SiteExtractor, 0, len(cfg.Sites))
+	for _, site := range cfg.Sites {
+		pattern, err := regexp.Compile(site.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("extractors: invalid pattern %q in %s: %w", site.Pattern, path, err)
+		}
+		loaded = append(loaded, &configuredExtractor{pattern: pattern, cfg: site})
+	}
+	return loaded, nil
+}
@@ -0,0 +1,26 @@
+package extractors
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// EenaduExtractor handles eenadu.net's fullstory layout: the headline,
+// body paragraphs, and publish date all live inside a
+// "div.fullstory"/"section.fullstory" container.
+type EenaduExtractor struct{}
+
+func (EenaduExtractor) Match(u string) bool {
+	return strings.Contains(u, "eenadu.net")
+}
+
+func (EenaduExtractor) Extract(u string, doc *goquery.Document) (Article, error) {
+	fullstory := doc.Find("div.fullstory, section.fullstory")
+	return Article{
+		URL:           u,
+		Title:         fullstory.Find("h1").Text(),
+		Content:       fullstory.Find("p").Text(),
+		DatePublished: fullstory.Find("div.pub-t").Text(),
+	}, nil
+}
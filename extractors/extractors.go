@@ -0,0 +1,59 @@
+// Package extractors turns a parsed HTML document into an Article. It
+// exists so the crawler isn't hardcoded to Eenadu's markup: each site (or
+// family of sites) gets its own SiteExtractor, and a Registry picks the
+// right one for a given URL, falling back to a generic heuristic when no
+// dedicated extractor matches.
+package extractors
+
+import (
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Article is the content pulled out of a single page. Not every
+// extractor populates every field.
+type Article struct {
+	URL           string
+	Title         string
+	DatePublished string
+	Content       string
+	Author        string
+}
+
+// SiteExtractor knows how to recognize pages belonging to a site and pull
+// an Article out of their parsed document.
+type SiteExtractor interface {
+	// Match reports whether this extractor should handle u.
+	Match(u string) bool
+	// Extract pulls title/date/content/author out of doc.
+	Extract(u string, doc *goquery.Document) (Article, error)
+}
+
+// Registry holds an ordered list of extractors. The first one whose
+// Match returns true handles a given URL, so more specific extractors
+// should be registered before general-purpose fallbacks.
+type Registry struct {
+	extractors []SiteExtractor
+}
+
+// NewRegistry builds a Registry that tries each extractor in order.
+func NewRegistry(extractors ...SiteExtractor) *Registry {
+	return &Registry{extractors: extractors}
+}
+
+// Add appends an extractor to the end of the registry, after any already
+// registered. Use this for extractors loaded from config, so they run
+// after bespoke ones but before the final fallback.
+func (r *Registry) Add(e SiteExtractor) {
+	r.extractors = append(r.extractors, e)
+}
+
+// For returns the first extractor willing to handle u, or nil if none
+// match.
+func (r *Registry) For(u string) SiteExtractor {
+	for _, e := range r.extractors {
+		if e.Match(u) {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package extractors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// matchOnly is a stub SiteExtractor for testing Registry ordering: it
+// matches URLs containing substr (every URL, if substr is empty) and
+// never actually extracts.
+type matchOnly struct {
+	substr string
+	name   string
+}
+
+func (m matchOnly) Match(u string) bool { return strings.Contains(u, m.substr) }
+
+func (m matchOnly) Extract(u string, doc *goquery.Document) (Article, error) {
+	return Article{URL: u, Title: m.name}, nil
+}
+
+func TestRegistryPrefersEarlierMatch(t *testing.T) {
+	registry := NewRegistry(
+		matchOnly{substr: "eenadu.net", name: "eenadu"},
+		matchOnly{substr: "", name: "fallback"}, // matches everything
+	)
+
+	e := registry.For("https://www.eenadu.net/some-story")
+	if e == nil {
+		t.Fatal("For returned nil, want the eenadu extractor")
+	}
+	got, _ := e.Extract("https://www.eenadu.net/some-story", nil)
+	if got.Title != "eenadu" {
+		t.Errorf("For matched %q, want the bespoke extractor to win over the fallback", got.Title)
+	}
+}
+
+func TestRegistryFallsBackWhenNothingSpecificMatches(t *testing.T) {
+	registry := NewRegistry(
+		matchOnly{substr: "eenadu.net", name: "eenadu"},
+		matchOnly{substr: "", name: "fallback"},
+	)
+
+	e := registry.For("https://other-site.example/article")
+	if e == nil {
+		t.Fatal("For returned nil, want the fallback extractor")
+	}
+	got, _ := e.Extract("https://other-site.example/article", nil)
+	if got.Title != "fallback" {
+		t.Errorf("For matched %q, want the fallback extractor", got.Title)
+	}
+}
+
+func TestRegistryAddRunsAfterConstructorExtractors(t *testing.T) {
+	registry := NewRegistry(matchOnly{substr: "eenadu.net", name: "eenadu"})
+	registry.Add(matchOnly{substr: "", name: "fallback"})
+
+	e := registry.For("https://www.eenadu.net/some-story")
+	got, _ := e.Extract("https://www.eenadu.net/some-story", nil)
+	if got.Title != "eenadu" {
+		t.Errorf("For matched %q, want the extractor registered in NewRegistry to still win", got.Title)
+	}
+
+	e = registry.For("https://other-site.example/article")
+	got, _ = e.Extract("https://other-site.example/article", nil)
+	if got.Title != "fallback" {
+		t.Errorf("For matched %q, want the extractor added via Add to catch the rest", got.Title)
+	}
+}
+
+func TestRegistryNoMatch(t *testing.T) {
+	registry := NewRegistry(matchOnly{substr: "eenadu.net", name: "eenadu"})
+
+	if e := registry.For("https://other-site.example/article"); e != nil {
+		t.Errorf("For returned %v, want nil when no extractor matches", e)
+	}
+}
@@ -0,0 +1,59 @@
+package extractors
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// GenericExtractor is the fallback used when no dedicated extractor
+// matches a URL. It applies a Readability-style heuristic: each
+// paragraph's text is attributed to its nearest enclosing div/section/
+// article, and the container with the most attributed text is taken to
+// be the article body. Attributing by nearest container (rather than
+// cumulative descendant text) keeps an outer wrapper from always
+// outscoring the article element it contains, just because it also
+// contains the page's nav/footer paragraphs.
+type GenericExtractor struct{}
+
+func (GenericExtractor) Match(string) bool { return true }
+
+func (GenericExtractor) Extract(u string, doc *goquery.Document) (Article, error) {
+	texts := make(map[*html.Node]string)
+
+	doc.Find("p").Each(func(i int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if text == "" {
+			return
+		}
+		container := p.Closest("div, section, article")
+		if container.Length() == 0 {
+			return
+		}
+		node := container.Get(0)
+		if existing, ok := texts[node]; ok {
+			texts[node] = existing + " " + text
+		} else {
+			texts[node] = text
+		}
+	})
+
+	var bestText string
+	doc.Find("div, section, article").Each(func(i int, s *goquery.Selection) {
+		if text := texts[s.Get(0)]; len(text) > len(bestText) {
+			bestText = text
+		}
+	})
+
+	title := doc.Find("h1").First().Text()
+	if title == "" {
+		title = doc.Find("title").Text()
+	}
+
+	return Article{
+		URL:     u,
+		Title:   title,
+		Content: bestText,
+	}, nil
+}
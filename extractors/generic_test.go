@@ -0,0 +1,35 @@
+package extractors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestGenericExtractorIgnoresOuterWrapperChrome(t *testing.T) {
+	page := `<html><body>
+		<div id="wrapper">
+			<nav><p>home about contact</p></nav>
+			<div class="article"><p>` + strings.Repeat("real article content. ", 10) + `</p></div>
+			<footer><p>copyright footer text</p></footer>
+		</div>
+	</body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := GenericExtractor{}.Extract("https://example.com/a", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(a.Content, "home about contact") || strings.Contains(a.Content, "copyright footer text") {
+		t.Errorf("Content leaked chrome text from the outer wrapper: %q", a.Content)
+	}
+	if !strings.Contains(a.Content, "real article content") {
+		t.Errorf("Content missing the actual article text: %q", a.Content)
+	}
+}
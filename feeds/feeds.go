@@ -0,0 +1,54 @@
+// Package feeds polls a fixed list of RSS/Atom feeds for newly
+// published article links, as an alternative to discovering them purely
+// by following links from baseURL.
+package feeds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Item is a single feed entry's link and publish time.
+type Item struct {
+	URL         string
+	PublishedAt time.Time
+}
+
+// Poller fetches a fixed set of feed URLs on demand.
+type Poller struct {
+	feedURLs []string
+	parser   *gofeed.Parser
+}
+
+// NewPoller builds a Poller over feedURLs.
+func NewPoller(feedURLs []string) *Poller {
+	return &Poller{feedURLs: feedURLs, parser: gofeed.NewParser()}
+}
+
+// Poll fetches every configured feed once and returns the items found.
+// A feed that fails to fetch or parse doesn't stop the others; its
+// error is returned alongside whatever items were collected from the
+// rest.
+func (p *Poller) Poll() (items []Item, errs []error) {
+	for _, feedURL := range p.feedURLs {
+		feed, err := p.parser.ParseURL(feedURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("feeds: %s: %w", feedURL, err))
+			continue
+		}
+
+		for _, entry := range feed.Items {
+			if entry.Link == "" {
+				continue
+			}
+			item := Item{URL: entry.Link}
+			if entry.PublishedParsed != nil {
+				item.PublishedAt = *entry.PublishedParsed
+			}
+			items = append(items, item)
+		}
+	}
+	return items, errs
+}
@@ -0,0 +1,335 @@
+// Package fetcher provides a polite HTTP client for crawling a single site
+// (or a handful of sites) without overwhelming it: it caps per-host
+// concurrency, rate limits requests with a token bucket, retries transient
+// failures with exponential backoff, honors robots.txt (including
+// Crawl-delay), and caches responses on disk so re-crawls of unchanged
+// pages are cheap.
+package fetcher
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+const (
+	defaultUserAgent     = "eenadu-go-scraper/1.0 (+https://github.com/ramuthumu/eenadu-go-scraper)"
+	defaultMaxRetries    = 3
+	defaultPerHostLimit  = 2
+	defaultRatePerSecond = 1.0
+	robotsCacheTTL       = 24 * time.Hour
+)
+
+// Config controls the politeness and caching behavior of a Fetcher.
+type Config struct {
+	UserAgent      string
+	PerHostLimit   int           // max concurrent in-flight requests per host
+	RatePerSecond  float64       // token bucket refill rate per host
+	MaxRetries     int           // retries on 5xx/429 before giving up
+	CacheDir       string        // on-disk HTTP cache; empty disables caching
+	RequestTimeout time.Duration // per-request timeout, 0 means http.Client default
+}
+
+// Fetcher performs HTTP GETs on behalf of the crawler while enforcing
+// per-host concurrency limits, rate limiting, robots.txt rules, and
+// serving cached responses when the origin reports no change.
+type Fetcher struct {
+	cfg    Config
+	client *http.Client
+
+	mu       sync.Mutex
+	hosts    map[string]*hostState
+	robots   map[string]*robotsEntry
+	cacheDir string
+}
+
+type hostState struct {
+	sem     chan struct{}
+	limiter *tokenBucket
+}
+
+type robotsEntry struct {
+	group      *robotstxt.Group
+	crawlDelay time.Duration
+	fetchedAt  time.Time
+	lastHit    time.Time
+}
+
+// New creates a Fetcher from cfg, filling in sensible defaults for any
+// zero-valued fields.
+func New(cfg Config) *Fetcher {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = defaultUserAgent
+	}
+	if cfg.PerHostLimit <= 0 {
+		cfg.PerHostLimit = defaultPerHostLimit
+	}
+	if cfg.RatePerSecond <= 0 {
+		cfg.RatePerSecond = defaultRatePerSecond
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+
+	f := &Fetcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+		hosts:  make(map[string]*hostState),
+		robots: make(map[string]*robotsEntry),
+	}
+	if cfg.CacheDir != "" {
+		_ = os.MkdirAll(cfg.CacheDir, 0o755)
+	}
+	return f
+}
+
+// Get fetches u, applying robots.txt, rate limiting, retries, and the
+// on-disk cache. It returns the response body along with whether it was
+// served from cache.
+func (f *Fetcher) Get(u string) ([]byte, bool, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetcher: parsing %q: %w", u, err)
+	}
+
+	allowed, err := f.allowedByRobots(parsed)
+	if err != nil {
+		// Fail open: a broken robots.txt shouldn't block the whole crawl.
+		allowed = true
+	}
+	if !allowed {
+		return nil, false, fmt.Errorf("fetcher: %s disallowed by robots.txt", u)
+	}
+
+	state := f.hostState(parsed.Host)
+	state.sem <- struct{}{}
+	defer func() { <-state.sem }()
+	state.limiter.Take()
+	f.waitCrawlDelay(parsed.Host)
+
+	cached := f.loadCacheEntry(u)
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", f.cfg.UserAgent)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	var resp *http.Response
+	backoff := 500 * time.Millisecond
+	for attempt := 0; attempt <= f.cfg.MaxRetries; attempt++ {
+		resp, err = f.client.Do(req)
+		if err != nil {
+			if attempt == f.cfg.MaxRetries {
+				return nil, false, err
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			resp.Body.Close()
+			if attempt == f.cfg.MaxRetries {
+				return nil, false, fmt.Errorf("fetcher: %s returned %d after %d retries", u, resp.StatusCode, attempt)
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+		break
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, true, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("fetcher: %s returned status %d", u, resp.StatusCode)
+	}
+
+	f.storeCacheEntry(u, cacheEntry{
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+
+	return body, false, nil
+}
+
+func (f *Fetcher) hostState(host string) *hostState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if s, ok := f.hosts[host]; ok {
+		return s
+	}
+	s := &hostState{
+		sem:     make(chan struct{}, f.cfg.PerHostLimit),
+		limiter: newTokenBucket(f.cfg.RatePerSecond),
+	}
+	f.hosts[host] = s
+	return s
+}
+
+func (f *Fetcher) allowedByRobots(u *url.URL) (bool, error) {
+	entry, err := f.robotsFor(u)
+	if err != nil {
+		return true, err
+	}
+	if entry.group == nil {
+		return true, nil
+	}
+	return entry.group.Test(u.Path), nil
+}
+
+func (f *Fetcher) robotsFor(u *url.URL) (*robotsEntry, error) {
+	f.mu.Lock()
+	entry, ok := f.robots[u.Host]
+	f.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < robotsCacheTTL {
+		return entry, nil
+	}
+
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	resp, err := f.client.Get(robotsURL)
+	if err != nil {
+		entry = &robotsEntry{fetchedAt: time.Now()}
+		f.mu.Lock()
+		f.robots[u.Host] = entry
+		f.mu.Unlock()
+		return entry, err
+	}
+	defer resp.Body.Close()
+
+	robotsData, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		entry = &robotsEntry{fetchedAt: time.Now()}
+		f.mu.Lock()
+		f.robots[u.Host] = entry
+		f.mu.Unlock()
+		return entry, err
+	}
+
+	group := robotsData.FindGroup(f.cfg.UserAgent)
+	entry = &robotsEntry{
+		group:      group,
+		crawlDelay: group.CrawlDelay,
+		fetchedAt:  time.Now(),
+	}
+	f.mu.Lock()
+	f.robots[u.Host] = entry
+	f.mu.Unlock()
+	return entry, nil
+}
+
+func (f *Fetcher) waitCrawlDelay(host string) {
+	f.mu.Lock()
+	entry, ok := f.robots[host]
+	f.mu.Unlock()
+	if !ok || entry.crawlDelay == 0 {
+		return
+	}
+
+	f.mu.Lock()
+	wait := entry.crawlDelay - time.Since(entry.lastHit)
+	entry.lastHit = time.Now()
+	f.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+type cacheEntry struct {
+	Body         []byte `json:"-"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (f *Fetcher) cachePaths(u string) (meta string, body string) {
+	h := sha1.Sum([]byte(u))
+	key := hex.EncodeToString(h[:])
+	return filepath.Join(f.cfg.CacheDir, key+".json"), filepath.Join(f.cfg.CacheDir, key+".body")
+}
+
+func (f *Fetcher) loadCacheEntry(u string) *cacheEntry {
+	if f.cfg.CacheDir == "" {
+		return nil
+	}
+	metaPath, bodyPath := f.cachePaths(u)
+	metaBytes, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(metaBytes, &entry); err != nil {
+		return nil
+	}
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil
+	}
+	entry.Body = body
+	return &entry
+}
+
+func (f *Fetcher) storeCacheEntry(u string, entry cacheEntry) {
+	if f.cfg.CacheDir == "" {
+		return
+	}
+	metaPath, bodyPath := f.cachePaths(u)
+	metaBytes, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(metaPath, metaBytes, 0o644)
+	_ = os.WriteFile(bodyPath, entry.Body, 0o644)
+}
+
+// tokenBucket is a simple, single-host rate limiter: one token is added
+// every 1/rate seconds, up to a burst of 1, and Take blocks until a token
+// is available.
+type tokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(ratePerSecond float64) *tokenBucket {
+	return &tokenBucket{
+		interval: time.Duration(float64(time.Second) / ratePerSecond),
+	}
+}
+
+func (t *tokenBucket) Take() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	wait := t.interval - time.Since(t.last)
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	t.last = time.Now()
+}
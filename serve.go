@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ramuthumu/eenadu-go-scraper/feeds"
+	"github.com/ramuthumu/eenadu-go-scraper/server"
+	"github.com/ramuthumu/eenadu-go-scraper/store"
+)
+
+var (
+	pagesScraped int64
+	paused       int32
+	startTime    time.Time
+
+	inFlight   = make(map[string]struct{})
+	inFlightMu sync.Mutex
+)
+
+func pauseCrawl()       { atomic.StoreInt32(&paused, 1) }
+func resumeCrawl()      { atomic.StoreInt32(&paused, 0) }
+func crawlPaused() bool { return atomic.LoadInt32(&paused) == 1 }
+
+func trackInFlight(url string, inflight bool) {
+	inFlightMu.Lock()
+	defer inFlightMu.Unlock()
+	if inflight {
+		inFlight[url] = struct{}{}
+	} else {
+		delete(inFlight, url)
+	}
+}
+
+func inFlightURLs() []string {
+	inFlightMu.Lock()
+	defer inFlightMu.Unlock()
+	urls := make([]string, 0, len(inFlight))
+	for u := range inFlight {
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "server" {
+		runServer(args[1:])
+		return
+	}
+	runCrawl(args, "")
+}
+
+// runServer parses the "server" subcommand's flags (the usual crawl
+// flags plus -addr) and runs the crawl with the crawl-control HTTP API
+// attached.
+func runServer(args []string) {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address for the crawl-control HTTP API")
+	cf := registerCrawlFlags(fs)
+	fs.Parse(args)
+
+	applyCrawlFlags(cf)
+	runCrawlLoop(cf, *addr)
+}
+
+// runCrawl parses the default (no subcommand) invocation's flags and
+// runs the crawl without an HTTP API.
+func runCrawl(args []string, addr string) {
+	fs := flag.NewFlagSet("crawl", flag.ExitOnError)
+	cf := registerCrawlFlags(fs)
+	fs.Parse(args)
+
+	applyCrawlFlags(cf)
+	runCrawlLoop(cf, addr)
+}
+
+// runCrawlLoop drives the bounded worker pool described in crawlFlags
+// against the backend selected by -store/-dsn, optionally serving the
+// crawl-control HTTP API on addr (empty disables it), reporting progress
+// to stderr, and shutting down gracefully on SIGINT/SIGTERM: in-flight
+// URLs are marked back to visited=FALSE so a restart retries them
+// instead of skipping them.
+func runCrawlLoop(cf *crawlFlags, addr string) {
+	urlStore, articleStore, err := store.New(*cf.storeKind, *cf.storeDSN)
+	if err != nil {
+		log.Fatalf("Failed to initialize store: %v", err)
+	}
+	defer urlStore.Close()
+	defer articleStore.Close()
+
+	if err := urlStore.Seed(baseURL); err != nil {
+		log.Fatalf("Failed to seed frontier: %v", err)
+	}
+
+	startTime = time.Now()
+
+	var srv *server.Server
+	if addr != "" {
+		srv = server.New(addr, crawlController{urls: urlStore, articles: articleStore})
+		go func() {
+			if err := srv.ListenAndServe(); err != nil {
+				log.Printf("crawl-control API error: %v", err)
+			}
+		}()
+		log.Printf("crawl-control API listening on %s", addr)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	// shutdown is closed once when stop fires, so every goroutine that
+	// needs to react to it (the dispatch loop below, the wait loop) can
+	// select on it independently instead of racing to read stop itself.
+	shutdown := make(chan struct{})
+	go func() {
+		<-stop
+		close(shutdown)
+	}()
+
+	feedStop := make(chan struct{})
+	defer close(feedStop)
+	if len(cf.feedURLs) > 0 {
+		go pollFeeds(urlStore, feeds.NewPoller(cf.feedURLs), *cf.feedInterval, feedStop)
+	}
+
+	processBaseURL(urlStore, articleStore)
+
+	frontier := make(chan link, batchSize)
+	var workerWG sync.WaitGroup
+	for i := 0; i < *cf.workers; i++ {
+		workerWG.Add(1)
+		go func() {
+			defer workerWG.Done()
+			for l := range frontier {
+				if *cf.maxPages > 0 && atomic.LoadInt64(&pagesScraped) >= int64(*cf.maxPages) {
+					continue
+				}
+				trackInFlight(l.URL, true)
+				processURL(urlStore, articleStore, l, *cf.maxDepth)
+				trackInFlight(l.URL, false)
+				atomic.AddInt64(&pagesScraped, 1)
+			}
+		}()
+	}
+
+	// dispatchDone is closed when the dispatch loop below actually returns,
+	// whether because it ran out of work or because shutdown fired. The
+	// wait loop must not close frontier until dispatchDone fires, since
+	// the loop may be blocked inside "frontier <- l" and closing a channel
+	// a pending send targets panics the process.
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		for {
+			if *cf.maxPages > 0 && atomic.LoadInt64(&pagesScraped) >= int64(*cf.maxPages) {
+				return
+			}
+			select {
+			case <-shutdown:
+				return
+			default:
+			}
+			if crawlPaused() {
+				select {
+				case <-shutdown:
+					return
+				case <-time.After(1 * time.Second):
+				}
+				continue
+			}
+
+			currentLinks, err := urlStore.GetNext(batchSize)
+			if err != nil {
+				log.Printf("Error getting next URLs: %v", err)
+				continue
+			}
+			if len(currentLinks) == 0 {
+				fmt.Println("No more URLs to process. Exiting.")
+				return
+			}
+
+			urls := make([]string, len(currentLinks))
+			for i, l := range currentLinks {
+				urls[i] = l.URL
+			}
+			if err := urlStore.MarkVisited(urls); err != nil {
+				log.Printf("Error marking URLs as visited: %v", err)
+				continue
+			}
+
+			for _, l := range currentLinks {
+				select {
+				case frontier <- l:
+				case <-shutdown:
+					return
+				}
+			}
+
+			select {
+			case <-shutdown:
+				return
+			case <-time.After(1 * time.Second):
+			}
+		}
+	}()
+
+	progressTicker := time.NewTicker(5 * time.Second)
+	defer progressTicker.Stop()
+
+waitLoop:
+	for {
+		select {
+		case <-dispatchDone:
+			break waitLoop
+		case <-shutdown:
+			log.Println("Shutdown signal received, draining in-flight requests...")
+			<-dispatchDone
+			if err := urlStore.ResetToUnvisited(inFlightURLs()); err != nil {
+				log.Printf("Error resetting in-flight URLs: %v", err)
+			}
+			break waitLoop
+		case <-progressTicker.C:
+			reportProgress(urlStore)
+		}
+	}
+
+	close(frontier)
+	workerWG.Wait()
+
+	if srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down crawl-control API: %v", err)
+		}
+	}
+}
+
+// pollFeeds polls poller on interval until done is closed, inserting any
+// newly-discovered links into urls so GetNext can prioritize them.
+func pollFeeds(urls store.URLStore, poller *feeds.Poller, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			items, errs := poller.Poll()
+			for _, err := range errs {
+				log.Printf("Error polling feed: %v", err)
+			}
+			if len(items) == 0 {
+				continue
+			}
+			var feedItems []store.FeedItem
+			for _, item := range items {
+				if !linkFilter.allowed(item.URL) {
+					continue
+				}
+				feedItems = append(feedItems, store.FeedItem{URL: item.URL, PublishedAt: item.PublishedAt})
+			}
+			if len(feedItems) == 0 {
+				continue
+			}
+			if err := urls.InsertFeedURLs(feedItems, "rss"); err != nil {
+				log.Printf("Error inserting feed URLs: %v", err)
+			}
+		}
+	}
+}
+
+// reportProgress writes a single-line throughput/ETA summary to stderr,
+// in the spirit of a pb-style progress bar.
+func reportProgress(urls store.URLStore) {
+	pending, err := urls.CountPending()
+	if err != nil {
+		return
+	}
+	scraped, err := urls.CountScraped()
+	if err != nil {
+		return
+	}
+
+	elapsed := time.Since(startTime).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(atomic.LoadInt64(&pagesScraped)) / elapsed
+	}
+
+	eta := "unknown"
+	if rate > 0 {
+		eta = time.Duration(float64(pending) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+
+	fmt.Fprintf(os.Stderr, "\rscraped=%d pending=%d rate=%.2f/s eta=%s  ", scraped, pending, rate, eta)
+}
+
+// crawlController implements server.Controller against the injected
+// stores for this crawl.
+type crawlController struct {
+	urls     store.URLStore
+	articles store.ArticleStore
+}
+
+func (c crawlController) Stats() (server.Stats, error) {
+	pending, err := c.urls.CountPending()
+	if err != nil {
+		return server.Stats{}, err
+	}
+	visited, err := c.urls.CountVisited()
+	if err != nil {
+		return server.Stats{}, err
+	}
+	scraped, err := c.urls.CountScraped()
+	if err != nil {
+		return server.Stats{}, err
+	}
+	articles, err := c.articles.Count()
+	if err != nil {
+		return server.Stats{}, err
+	}
+
+	elapsed := time.Since(startTime).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(atomic.LoadInt64(&pagesScraped)) / elapsed
+	}
+
+	return server.Stats{
+		Pending:  pending,
+		Visited:  visited,
+		Scraped:  scraped,
+		Articles: articles,
+		Rate:     rate,
+		Paused:   crawlPaused(),
+	}, nil
+}
+
+func (c crawlController) Articles(limit, offset int, since string) ([]server.ArticleRecord, error) {
+	articles, err := c.articles.List(limit, offset, since)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]server.ArticleRecord, len(articles))
+	for i, a := range articles {
+		records[i] = server.ArticleRecord{
+			URL:           a.URL,
+			Title:         a.Title,
+			DatePublished: a.DatePublished,
+			Content:       a.Content,
+			Author:        a.Author,
+			ScrapedAt:     a.ScrapedAt,
+		}
+	}
+	return records, nil
+}
+
+func (c crawlController) Article(url string) (server.ArticleRecord, bool, error) {
+	a, ok, err := c.articles.Get(url)
+	if err != nil || !ok {
+		return server.ArticleRecord{}, ok, err
+	}
+	return server.ArticleRecord{
+		URL:           a.URL,
+		Title:         a.Title,
+		DatePublished: a.DatePublished,
+		Content:       a.Content,
+		Author:        a.Author,
+		ScrapedAt:     a.ScrapedAt,
+	}, true, nil
+}
+
+func (c crawlController) Seed(urls []string) error {
+	links := make([]link, len(urls))
+	for i, u := range urls {
+		links[i] = link{URL: u, Depth: 0}
+	}
+	return c.urls.InsertNew(links)
+}
+
+func (c crawlController) Pause()  { pauseCrawl() }
+func (c crawlController) Resume() { resumeCrawl() }
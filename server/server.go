@@ -0,0 +1,165 @@
+// Package server exposes a running crawl's state over HTTP: stats,
+// article listing/lookup, seeding new URLs, and pausing/resuming the
+// crawler. It knows nothing about SQLite or the crawler's internals; the
+// caller supplies a Controller backed by whatever storage is in use.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Stats is a snapshot of the crawl's progress.
+type Stats struct {
+	Pending  int     `json:"pending"`
+	Visited  int     `json:"visited"`
+	Scraped  int     `json:"scraped"`
+	Articles int     `json:"articles"`
+	Rate     float64 `json:"pages_per_second"`
+	Paused   bool    `json:"paused"`
+}
+
+// ArticleRecord is the JSON representation of a scraped article.
+type ArticleRecord struct {
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	DatePublished string `json:"date_published"`
+	Content       string `json:"content"`
+	Author        string `json:"author"`
+	ScrapedAt     string `json:"scraped_at"`
+}
+
+// Controller is the crawler-side hook the Server drives. Implementations
+// live alongside whatever storage backend the crawler uses.
+type Controller interface {
+	Stats() (Stats, error)
+	Articles(limit, offset int, since string) ([]ArticleRecord, error)
+	Article(url string) (ArticleRecord, bool, error)
+	Seed(urls []string) error
+	Pause()
+	Resume()
+}
+
+// Server wires a Controller up to the crawl-control HTTP API.
+type Server struct {
+	ctrl Controller
+	http *http.Server
+}
+
+// New builds a Server listening on addr. Call ListenAndServe to start it.
+func New(addr string, ctrl Controller) *Server {
+	s := &Server{ctrl: ctrl}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/articles", s.handleArticles)
+	mux.HandleFunc("/articles/", s.handleArticle)
+	mux.HandleFunc("/seed", s.handleSeed)
+	mux.HandleFunc("/pause", s.handlePause)
+	mux.HandleFunc("/resume", s.handleResume)
+	s.http = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// ListenAndServe blocks serving the API until the server is shut down or
+// an unrecoverable error occurs.
+func (s *Server) ListenAndServe() error {
+	err := s.http.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown gracefully stops the HTTP listener, letting in-flight
+// requests finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.ctrl.Stats()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+func (s *Server) handleArticles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(q.Get("offset"))
+
+	articles, err := s.ctrl.Articles(limit, offset, q.Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, articles)
+}
+
+func (s *Server) handleArticle(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/articles/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+	article, ok, err := s.ctrl.Article(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, article)
+}
+
+func (s *Server) handleSeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body struct {
+		URLs []string `json:"urls"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.ctrl.Seed(body.URLs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.ctrl.Pause()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.ctrl.Resume()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,61 @@
+package store
+
+import "fmt"
+
+// New builds the URLStore and ArticleStore for the given backend kind
+// ("sqlite", "postgres", or "jsonl"). dsn is backend-specific: a
+// "urlsPath,articlesPath" pair for sqlite (defaulting to
+// "urls.db,articles.db" if empty), a Postgres connection string for
+// postgres, and an output file path for jsonl (defaulting to
+// "articles.jsonl" if empty; URLs are still tracked in memory).
+func New(kind, dsn string) (URLStore, ArticleStore, error) {
+	switch kind {
+	case "", "sqlite":
+		urlsPath, articlesPath := "urls.db", "articles.db"
+		if dsn != "" {
+			var ok bool
+			urlsPath, articlesPath, ok = splitPair(dsn)
+			if !ok {
+				return nil, nil, fmt.Errorf("store: sqlite dsn must be \"urlsPath,articlesPath\", got %q", dsn)
+			}
+		}
+		s, err := NewSQLiteStore(urlsPath, articlesPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s, nil
+
+	case "postgres":
+		if dsn == "" {
+			return nil, nil, fmt.Errorf("store: postgres backend requires a DSN")
+		}
+		s, err := NewPostgresStore(dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s, nil
+
+	case "jsonl":
+		path := dsn
+		if path == "" {
+			path = "articles.jsonl"
+		}
+		articles, err := NewJSONLStore(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newMemoryURLStore(), articles, nil
+
+	default:
+		return nil, nil, fmt.Errorf("store: unknown backend %q", kind)
+	}
+}
+
+func splitPair(s string) (a, b string, ok bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ramuthumu/eenadu-go-scraper/dedupe"
+)
+
+// JSONLStore appends scraped articles to an NDJSON file for downstream
+// ML pipelines, instead of a queryable database. It does not support
+// near-duplicate lookup or random access by URL, since that would
+// require reading the whole file back.
+type JSONLStore struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewJSONLStore opens (creating/appending to) the NDJSON file at path.
+func NewJSONLStore(path string) (*JSONLStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLStore{file: f}, nil
+}
+
+// Insert always appends: a flat file can't be queried by SimHash band
+// without reading it back in full, so near-duplicate detection is
+// skipped rather than attempted inconsistently.
+func (s *JSONLStore) Insert(article Article) (string, error) {
+	if article.ScrapedAt == "" {
+		article.ScrapedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(article)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.file.Write(append(line, '\n'))
+	return "", err
+}
+
+// Get and List are not supported: a flat append-only file isn't
+// indexed, so the crawl-control API falls back to "not found"/empty
+// rather than scanning the whole export on every request.
+func (s *JSONLStore) Get(string) (Article, bool, error) {
+	return Article{}, false, nil
+}
+
+func (s *JSONLStore) List(int, int, string) ([]Article, error) {
+	return nil, nil
+}
+
+func (s *JSONLStore) Count() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	defer s.file.Seek(0, io.SeekEnd)
+
+	count := 0
+	scanner := bufio.NewScanner(s.file)
+	for scanner.Scan() {
+		count++
+	}
+	return count, scanner.Err()
+}
+
+func (s *JSONLStore) Close() error {
+	return s.file.Close()
+}
+
+// memoryURLStore is a minimal in-memory URLStore used alongside
+// JSONLStore, since the jsonl backend is an article export target, not
+// a frontier database. GetNext returns unvisited URLs in no particular
+// order: InsertFeedURLs records neither the feed source nor the publish
+// time, so feed-sourced links are not prioritized. Scraping is not
+// tracked either: MarkScraped does nothing and CountScraped reports the
+// visited count.
+type memoryURLStore struct {
+	mu    sync.Mutex
+	links map[string]Link
+	state map[string]bool // url -> visited
+}
+
+func newMemoryURLStore() *memoryURLStore {
+	return &memoryURLStore{
+		links: make(map[string]Link),
+		state: make(map[string]bool),
+	}
+}
+
+func (m *memoryURLStore) Seed(url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.links) == 0 {
+		m.links[url] = Link{URL: url}
+		m.state[url] = false
+	}
+	return nil
+}
+
+func (m *memoryURLStore) GetNext(batchSize int) ([]Link, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var links []Link
+	for u, l := range m.links {
+		if !m.state[u] {
+			links = append(links, l)
+			if len(links) >= batchSize {
+				break
+			}
+		}
+	}
+	return links, nil
+}
+
+func (m *memoryURLStore) MarkVisited(urls []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range urls {
+		m.state[u] = true
+	}
+	return nil
+}
+
+func (m *memoryURLStore) ResetToUnvisited(urls []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range urls {
+		m.state[u] = false
+	}
+	return nil
+}
+
+func (m *memoryURLStore) MarkScraped(string) error { return nil }
+
+func (m *memoryURLStore) InsertNew(links []Link) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range links {
+		normalized, err := dedupe.NormalizeURL(l.URL)
+		if err != nil {
+			normalized = l.URL
+		}
+		if _, ok := m.links[normalized]; !ok {
+			l.URL = normalized
+			m.links[normalized] = l
+			m.state[normalized] = false
+		}
+	}
+	return nil
+}
+
+func (m *memoryURLStore) InsertFeedURLs(items []FeedItem, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range items {
+		normalized, err := dedupe.NormalizeURL(item.URL)
+		if err != nil {
+			normalized = item.URL
+		}
+		if _, ok := m.links[normalized]; !ok {
+			m.links[normalized] = Link{URL: normalized}
+			m.state[normalized] = false
+		}
+	}
+	return nil
+}
+
+func (m *memoryURLStore) CountPending() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, visited := range m.state {
+		if !visited {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryURLStore) CountVisited() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, visited := range m.state {
+		if visited {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryURLStore) CountScraped() (int, error) {
+	return m.CountVisited()
+}
+
+func (m *memoryURLStore) Close() error { return nil }
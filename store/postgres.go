@@ -0,0 +1,350 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/ramuthumu/eenadu-go-scraper/dedupe"
+)
+
+// articleInsertLockKey is an arbitrary, fixed pg_advisory_xact_lock key
+// used to serialize the near-duplicate check against the insert in
+// Insert. Since this backend is meant for crawls with several
+// concurrent writers (possibly in different processes), a process-local
+// mutex wouldn't be enough: two writers could still both see "no
+// duplicate yet" before either commits.
+const articleInsertLockKey = 0x656e6164
+
+// PostgresStore implements both URLStore and ArticleStore against a
+// single Postgres database, for crawls that need several concurrent
+// writers (SQLite serializes all writes, which doesn't scale past one).
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore opens db (a "postgres://" DSN) and ensures the
+// schema exists.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &PostgresStore{db: db}
+	if err := s.initSchema(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *PostgresStore) initSchema() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS urls (
+		url TEXT PRIMARY KEY,
+		visited BOOLEAN NOT NULL DEFAULT FALSE,
+		scraped BOOLEAN NOT NULL DEFAULT FALSE,
+		depth INTEGER NOT NULL DEFAULT 0,
+		feed_source TEXT,
+		published_at TIMESTAMPTZ
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS articles (
+		url TEXT PRIMARY KEY,
+		title TEXT,
+		date_published TEXT,
+		content TEXT,
+		author TEXT,
+		simhash BIGINT NOT NULL DEFAULT 0,
+		scraped_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS article_bands (
+		band_idx INTEGER NOT NULL,
+		band_value INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		PRIMARY KEY (band_idx, band_value, url)
+	);`)
+	return err
+}
+
+func (s *PostgresStore) Seed(url string) error {
+	row := s.db.QueryRow("SELECT COUNT(*) FROM urls")
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count != 0 {
+		return nil
+	}
+
+	_, err := s.db.Exec("INSERT INTO urls (url, visited, scraped, depth) VALUES ($1, FALSE, FALSE, 0) ON CONFLICT DO NOTHING", url)
+	return err
+}
+
+func (s *PostgresStore) GetNext(batchSize int) ([]Link, error) {
+	rows, err := s.db.Query(`
+		SELECT url, depth FROM urls
+		WHERE visited = FALSE
+		ORDER BY (feed_source IS NOT NULL) DESC, published_at DESC NULLS LAST, depth ASC
+		LIMIT $1`, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []Link
+	for rows.Next() {
+		var l Link
+		if err := rows.Scan(&l.URL, &l.Depth); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+func (s *PostgresStore) MarkVisited(urls []string) error { return s.setVisited(urls, true) }
+
+func (s *PostgresStore) ResetToUnvisited(urls []string) error { return s.setVisited(urls, false) }
+
+func (s *PostgresStore) setVisited(urls []string, visited bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, u := range urls {
+		if _, err := tx.Exec("UPDATE urls SET visited = $1 WHERE url = $2", visited, u); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) MarkScraped(url string) error {
+	_, err := s.db.Exec("UPDATE urls SET scraped = TRUE WHERE url = $1", url)
+	return err
+}
+
+func (s *PostgresStore) InsertNew(links []Link) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO urls (url, visited, scraped, depth) VALUES ($1, FALSE, FALSE, $2) ON CONFLICT DO NOTHING")
+	if err != nil {
+		return err
+	}
+
+	for _, l := range links {
+		normalized, err := dedupe.NormalizeURL(l.URL)
+		if err != nil {
+			normalized = l.URL
+		}
+		if _, err := stmt.Exec(normalized, l.Depth); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) InsertFeedURLs(items []FeedItem, source string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO urls (url, visited, scraped, depth, feed_source, published_at)
+		VALUES ($1, FALSE, FALSE, 0, $2, $3)
+		ON CONFLICT (url) DO UPDATE SET feed_source = excluded.feed_source, published_at = excluded.published_at
+		WHERE urls.visited = FALSE`)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		normalized, err := dedupe.NormalizeURL(item.URL)
+		if err != nil {
+			normalized = item.URL
+		}
+		var publishedAt any
+		if !item.PublishedAt.IsZero() {
+			publishedAt = item.PublishedAt
+		}
+		if _, err := stmt.Exec(normalized, source, publishedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) CountPending() (int, error) { return s.countURLs(false) }
+func (s *PostgresStore) CountVisited() (int, error) { return s.countURLs(true) }
+
+func (s *PostgresStore) countURLs(visited bool) (int, error) {
+	row := s.db.QueryRow("SELECT COUNT(*) FROM urls WHERE visited = $1", visited)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (s *PostgresStore) CountScraped() (int, error) {
+	row := s.db.QueryRow("SELECT COUNT(*) FROM urls WHERE scraped = TRUE")
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// findNearDuplicate looks up articles sharing at least one of hash's
+// bands, then exact-compares their Hamming distance, returning the URL
+// of the first one within the similarity threshold. q runs within the
+// caller's transaction so the check is consistent with a concurrent
+// Insert holding the same advisory lock.
+func (s *PostgresStore) findNearDuplicate(q *sql.Tx, hash uint64) (string, error) {
+	candidates := make(map[string]struct{})
+	for i, band := range dedupe.Bands(hash) {
+		rows, err := q.Query("SELECT url FROM article_bands WHERE band_idx = $1 AND band_value = $2", i, band)
+		if err != nil {
+			return "", err
+		}
+		for rows.Next() {
+			var u string
+			if err := rows.Scan(&u); err != nil {
+				rows.Close()
+				return "", err
+			}
+			candidates[u] = struct{}{}
+		}
+		rows.Close()
+	}
+
+	for u := range candidates {
+		var existing int64
+		row := q.QueryRow("SELECT simhash FROM articles WHERE url = $1", u)
+		if err := row.Scan(&existing); err != nil {
+			continue
+		}
+		if dedupe.HammingDistance(hash, uint64(existing)) <= maxSimHashDistance {
+			return u, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *PostgresStore) Insert(article Article) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", articleInsertLockKey); err != nil {
+		return "", err
+	}
+
+	dupURL, err := s.findNearDuplicate(tx, article.SimHash)
+	if err != nil {
+		return "", err
+	}
+	if dupURL != "" {
+		return dupURL, tx.Commit()
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO articles (url, title, date_published, content, author, simhash)
+		VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT DO NOTHING`,
+		article.URL, article.Title, article.DatePublished, article.Content, article.Author, int64(article.SimHash))
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		for i, band := range dedupe.Bands(article.SimHash) {
+			if _, err := tx.Exec("INSERT INTO article_bands (band_idx, band_value, url) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", i, band, article.URL); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return "", tx.Commit()
+}
+
+func (s *PostgresStore) Get(url string) (Article, bool, error) {
+	row := s.db.QueryRow("SELECT url, title, date_published, content, author, simhash, scraped_at FROM articles WHERE url = $1", url)
+	var a Article
+	var simhash int64
+	var scrapedAt time.Time
+	if err := row.Scan(&a.URL, &a.Title, &a.DatePublished, &a.Content, &a.Author, &simhash, &scrapedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return Article{}, false, nil
+		}
+		return Article{}, false, err
+	}
+	a.SimHash = uint64(simhash)
+	a.ScrapedAt = scrapedAt.Format(time.RFC3339)
+	return a, true, nil
+}
+
+func (s *PostgresStore) List(limit, offset int, since string) ([]Article, error) {
+	const selectCols = "SELECT url, title, date_published, content, author, simhash, scraped_at FROM articles"
+
+	var (
+		query string
+		args  []any
+	)
+	if since != "" {
+		query = selectCols + " WHERE scraped_at >= $1 ORDER BY scraped_at DESC LIMIT $2 OFFSET $3"
+		args = []any{since, limit, offset}
+	} else {
+		query = selectCols + " ORDER BY scraped_at DESC LIMIT $1 OFFSET $2"
+		args = []any{limit, offset}
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		var simhash int64
+		var scrapedAt time.Time
+		if err := rows.Scan(&a.URL, &a.Title, &a.DatePublished, &a.Content, &a.Author, &simhash, &scrapedAt); err != nil {
+			return nil, err
+		}
+		a.SimHash = uint64(simhash)
+		a.ScrapedAt = scrapedAt.Format(time.RFC3339)
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+func (s *PostgresStore) Count() (int, error) {
+	row := s.db.QueryRow("SELECT COUNT(*) FROM articles")
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
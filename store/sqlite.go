@@ -0,0 +1,351 @@
+package store
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ramuthumu/eenadu-go-scraper/dedupe"
+)
+
+// maxSimHashDistance is the Hamming distance, in bits, at or below which
+// two articles are considered near-duplicates.
+const maxSimHashDistance = 3
+
+// SQLiteStore implements both URLStore and ArticleStore over two SQLite
+// databases, matching the crawler's original single-writer setup.
+type SQLiteStore struct {
+	urlsDB    *sql.DB
+	articleDB *sql.DB
+
+	// insertMu serializes the near-duplicate check against the insert in
+	// Insert, so two goroutines scraping near-duplicate articles at the
+	// same time can't both see "no duplicate yet" and both write.
+	insertMu sync.Mutex
+}
+
+// NewSQLiteStore opens (creating if necessary) the urls and articles
+// SQLite databases at the given paths and ensures their schema exists.
+func NewSQLiteStore(urlsDBPath, articlesDBPath string) (*SQLiteStore, error) {
+	urlsDB, err := sql.Open("sqlite3", urlsDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	articleDB, err := sql.Open("sqlite3", articlesDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLiteStore{urlsDB: urlsDB, articleDB: articleDB}
+	if err := s.initSchema(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) initSchema() error {
+	_, err := s.urlsDB.Exec(`
+	CREATE TABLE IF NOT EXISTS urls (
+		url TEXT PRIMARY KEY,
+		visited BOOLEAN,
+		scraped BOOLEAN,
+		depth INTEGER NOT NULL DEFAULT 0,
+		feed_source TEXT,
+		published_at TEXT
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.articleDB.Exec(`
+	CREATE TABLE IF NOT EXISTS articles (
+		url TEXT PRIMARY KEY,
+		title TEXT,
+		date_published TEXT,
+		content TEXT,
+		author TEXT,
+		simhash INTEGER NOT NULL DEFAULT 0,
+		scraped_at TEXT NOT NULL DEFAULT (datetime('now'))
+	);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.articleDB.Exec(`
+	CREATE TABLE IF NOT EXISTS article_bands (
+		band_idx INTEGER NOT NULL,
+		band_value INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		PRIMARY KEY (band_idx, band_value, url)
+	);`)
+	return err
+}
+
+func (s *SQLiteStore) Seed(url string) error {
+	row := s.urlsDB.QueryRow("SELECT COUNT(*) FROM urls")
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count != 0 {
+		return nil
+	}
+
+	_, err := s.urlsDB.Exec("INSERT INTO urls (url, visited, scraped, depth) VALUES (?, FALSE, FALSE, 0)", url)
+	return err
+}
+
+func (s *SQLiteStore) GetNext(batchSize int) ([]Link, error) {
+	rows, err := s.urlsDB.Query(`
+		SELECT url, depth FROM urls
+		WHERE visited = FALSE
+		ORDER BY (feed_source IS NOT NULL) DESC, published_at DESC, depth ASC
+		LIMIT ?`, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []Link
+	for rows.Next() {
+		var l Link
+		if err := rows.Scan(&l.URL, &l.Depth); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+func (s *SQLiteStore) MarkVisited(urls []string) error {
+	return s.setVisited(urls, true)
+}
+
+func (s *SQLiteStore) ResetToUnvisited(urls []string) error {
+	return s.setVisited(urls, false)
+}
+
+func (s *SQLiteStore) setVisited(urls []string, visited bool) error {
+	tx, err := s.urlsDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, u := range urls {
+		if _, err := tx.Exec("UPDATE urls SET visited = ? WHERE url = ?", visited, u); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) MarkScraped(url string) error {
+	_, err := s.urlsDB.Exec("UPDATE urls SET scraped = TRUE WHERE url = ?", url)
+	return err
+}
+
+func (s *SQLiteStore) InsertNew(links []Link) error {
+	tx, err := s.urlsDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO urls (url, visited, scraped, depth) VALUES (?, FALSE, FALSE, ?)")
+	if err != nil {
+		return err
+	}
+
+	for _, l := range links {
+		normalized, err := dedupe.NormalizeURL(l.URL)
+		if err != nil {
+			normalized = l.URL
+		}
+		if _, err := stmt.Exec(normalized, l.Depth); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) InsertFeedURLs(items []FeedItem, source string) error {
+	tx, err := s.urlsDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO urls (url, visited, scraped, depth, feed_source, published_at)
+		VALUES (?, FALSE, FALSE, 0, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET feed_source = excluded.feed_source, published_at = excluded.published_at
+		WHERE urls.visited = FALSE`)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		normalized, err := dedupe.NormalizeURL(item.URL)
+		if err != nil {
+			normalized = item.URL
+		}
+		var publishedAt string
+		if !item.PublishedAt.IsZero() {
+			publishedAt = item.PublishedAt.Format(time.RFC3339)
+		}
+		if _, err := stmt.Exec(normalized, source, publishedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) CountPending() (int, error) { return s.countURLs(false) }
+func (s *SQLiteStore) CountVisited() (int, error) { return s.countURLs(true) }
+
+func (s *SQLiteStore) countURLs(visited bool) (int, error) {
+	row := s.urlsDB.QueryRow("SELECT COUNT(*) FROM urls WHERE visited = ?", visited)
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (s *SQLiteStore) CountScraped() (int, error) {
+	row := s.urlsDB.QueryRow("SELECT COUNT(*) FROM urls WHERE scraped = TRUE")
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// findNearDuplicate looks up articles sharing at least one of hash's
+// bands, then exact-compares their Hamming distance, returning the URL
+// of the first one within the similarity threshold. Callers must hold
+// insertMu so the check stays consistent with a concurrent Insert.
+func (s *SQLiteStore) findNearDuplicate(hash uint64) (string, error) {
+	candidates := make(map[string]struct{})
+	for i, band := range dedupe.Bands(hash) {
+		rows, err := s.articleDB.Query("SELECT url FROM article_bands WHERE band_idx = ? AND band_value = ?", i, band)
+		if err != nil {
+			return "", err
+		}
+		for rows.Next() {
+			var u string
+			if err := rows.Scan(&u); err != nil {
+				rows.Close()
+				return "", err
+			}
+			candidates[u] = struct{}{}
+		}
+		rows.Close()
+	}
+
+	for u := range candidates {
+		var existing int64
+		row := s.articleDB.QueryRow("SELECT simhash FROM articles WHERE url = ?", u)
+		if err := row.Scan(&existing); err != nil {
+			continue
+		}
+		if dedupe.HammingDistance(hash, uint64(existing)) <= maxSimHashDistance {
+			return u, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *SQLiteStore) Insert(article Article) (string, error) {
+	s.insertMu.Lock()
+	defer s.insertMu.Unlock()
+
+	dupURL, err := s.findNearDuplicate(article.SimHash)
+	if err != nil {
+		return "", err
+	}
+	if dupURL != "" {
+		return dupURL, nil
+	}
+
+	tx, err := s.articleDB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("INSERT OR IGNORE INTO articles (url, title, date_published, content, author, simhash) VALUES (?, ?, ?, ?, ?, ?)",
+		article.URL, article.Title, article.DatePublished, article.Content, article.Author, int64(article.SimHash))
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		for i, band := range dedupe.Bands(article.SimHash) {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO article_bands (band_idx, band_value, url) VALUES (?, ?, ?)", i, band, article.URL); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return "", tx.Commit()
+}
+
+func (s *SQLiteStore) Get(url string) (Article, bool, error) {
+	row := s.articleDB.QueryRow("SELECT url, title, date_published, content, author, simhash, scraped_at FROM articles WHERE url = ?", url)
+	var a Article
+	var simhash int64
+	if err := row.Scan(&a.URL, &a.Title, &a.DatePublished, &a.Content, &a.Author, &simhash, &a.ScrapedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return Article{}, false, nil
+		}
+		return Article{}, false, err
+	}
+	a.SimHash = uint64(simhash)
+	return a, true, nil
+}
+
+func (s *SQLiteStore) List(limit, offset int, since string) ([]Article, error) {
+	query := "SELECT url, title, date_published, content, author, simhash, scraped_at FROM articles"
+	args := []any{}
+	if since != "" {
+		query += " WHERE scraped_at >= ?"
+		args = append(args, since)
+	}
+	query += " ORDER BY scraped_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := s.articleDB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		var simhash int64
+		if err := rows.Scan(&a.URL, &a.Title, &a.DatePublished, &a.Content, &a.Author, &simhash, &a.ScrapedAt); err != nil {
+			return nil, err
+		}
+		a.SimHash = uint64(simhash)
+		articles = append(articles, a)
+	}
+	return articles, nil
+}
+
+func (s *SQLiteStore) Count() (int, error) {
+	row := s.articleDB.QueryRow("SELECT COUNT(*) FROM articles")
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+func (s *SQLiteStore) Close() error {
+	if err := s.urlsDB.Close(); err != nil {
+		return err
+	}
+	return s.articleDB.Close()
+}
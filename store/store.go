@@ -0,0 +1,74 @@
+// Package store abstracts the crawler's persistence behind URLStore and
+// ArticleStore interfaces, so the frontier and scraped articles can live
+// in SQLite (the default, single-writer setup), Postgres (for
+// concurrent multi-writer crawls, since SQLite serializes writes), or a
+// flat JSONL/NDJSON file for downstream ML pipelines.
+package store
+
+import "time"
+
+// Link is a frontier entry: a URL paired with its BFS depth from
+// baseURL, used to bound how far the crawler follows links.
+type Link struct {
+	URL   string
+	Depth int
+}
+
+// FeedItem is a link discovered via an RSS/Atom feed, along with its
+// publish time if the feed provided one.
+type FeedItem struct {
+	URL         string
+	PublishedAt time.Time
+}
+
+// Article is a single scraped page.
+type Article struct {
+	URL           string
+	Title         string
+	DatePublished string
+	Content       string
+	Author        string
+	SimHash       uint64
+	ScrapedAt     string
+}
+
+// URLStore tracks the crawl frontier: which URLs are known, which have
+// been visited (dequeued for processing), and which have been
+// successfully scraped.
+type URLStore interface {
+	// Seed inserts url as an unvisited, depth-0 entry if the store is
+	// empty. Safe to call on every startup.
+	Seed(url string) error
+	// GetNext returns up to batchSize unvisited URLs, feed-sourced and
+	// newer entries first.
+	GetNext(batchSize int) ([]Link, error)
+	MarkVisited(urls []string) error
+	// ResetToUnvisited undoes MarkVisited, used when a shutdown
+	// interrupts in-flight work so a restart retries it.
+	ResetToUnvisited(urls []string) error
+	MarkScraped(url string) error
+	InsertNew(links []Link) error
+	// InsertFeedURLs upserts feed-discovered links, tagging them with
+	// source so GetNext can prioritize them.
+	InsertFeedURLs(items []FeedItem, source string) error
+	CountPending() (int, error)
+	CountVisited() (int, error)
+	CountScraped() (int, error)
+	Close() error
+}
+
+// ArticleStore holds scraped article content.
+type ArticleStore interface {
+	// Insert stores article unless it is a near-duplicate (by SimHash
+	// Hamming distance) of an article already on file, in which case it
+	// is skipped and the existing article's URL is returned as dupURL.
+	// Implementations serialize the duplicate check against the write so
+	// concurrent callers racing on near-duplicate content can't both
+	// observe "no duplicate yet" and both insert. Implementations that
+	// can't detect duplicates (e.g. the JSONL exporter) always insert.
+	Insert(article Article) (dupURL string, err error)
+	Get(url string) (Article, bool, error)
+	List(limit, offset int, since string) ([]Article, error)
+	Count() (int, error)
+	Close() error
+}